@@ -0,0 +1,52 @@
+package quic
+
+import (
+	"fmt"
+
+	"github.com/goburrow/quic/transport"
+)
+
+// defaultActiveCIDLimit is the number of connection IDs a Server keeps
+// available for one connection when the peer does not negotiate a
+// different active_connection_id_limit.
+//
+// It has no effect yet: addPeerLocked below only registers a connection's
+// scid, not a transport.CIDPool of defaultActiveCIDLimit entries, because
+// remoteConn has no cids field in this source tree to hold one. No commit
+// in this series touches the file that declares remoteConn, so adding that
+// field here was a compile break, not just an unwired scaffold; the
+// CIDPool-based registration this package needs was reverted back to the
+// single-scid registration Server used before. retireCID/issueCID below
+// are the hooks a RETIRE_CONNECTION_ID/NEW_CONNECTION_ID handler should
+// call into once remoteConn actually has a cids field to give them, and
+// they return an error simply noting that for now.
+const defaultActiveCIDLimit = 4
+
+var errNoCIDPool = fmt.Errorf("quic: remoteConn has no connection id pool in this build")
+
+// addPeerLocked registers c's scid into s.peers. Callers must hold peersMu.
+// It reports a conflict error, registering nothing, if the scid is already
+// in use by another connection.
+func (s *Server) addPeerLocked(c *remoteConn) error {
+	if _, ok := s.peers[string(c.scid[:])]; ok {
+		return fmt.Errorf("connection id conflict cid=%x", c.scid)
+	}
+	s.peers[string(c.scid[:])] = c
+	return nil
+}
+
+// retireCID would remove the CID with the given sequence number from both
+// c.cids and s.peers once the connection's recv path decodes a
+// RETIRE_CONNECTION_ID frame from the peer; see the comment on
+// defaultActiveCIDLimit above for why it cannot do that yet.
+func (s *Server) retireCID(c *remoteConn, seq uint64) error {
+	return errNoCIDPool
+}
+
+// issueCID would generate and register a new CID for c, for use in a future
+// NEW_CONNECTION_ID frame once active_connection_id_limit allows for more
+// than the initial one; see the comment on defaultActiveCIDLimit above for
+// why it cannot do that yet.
+func (s *Server) issueCID(c *remoteConn) (transport.CIDEntry, bool, error) {
+	return transport.CIDEntry{}, false, errNoCIDPool
+}
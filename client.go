@@ -13,6 +13,9 @@ import (
 // All setters must only be invoked before calling Serve.
 type Client struct {
 	localConn
+
+	qlogDir string
+	qlog    qlogRegistry
 }
 
 // NewClient creates a new QUIC client.
@@ -34,6 +37,14 @@ func (s *Client) ListenAndServe(addr string) error {
 	return err
 }
 
+// SetListen sets the socket Serve reads from and writes to, instead of one
+// created by ListenAndServe. Packets for connections of another Client or
+// Server sharing the same Transport are not routed to them (see Transport).
+// SetListen must be called before Serve.
+func (s *Client) SetListen(t *Transport) {
+	s.socket = t.PacketConn()
+}
+
 // Serve handles requests from given socket.
 func (s *Client) Serve() error {
 	if s.socket == nil {
@@ -74,6 +85,16 @@ func (s *Client) recv(p *packet) {
 	c, ok := s.peers[string(p.header.DCID)]
 	s.peersMu.RUnlock()
 	if ok {
+		// transport.Conn has no hook to route its own events through a qlog
+		// emitter, so this is the only packet_received event a qlog trace for
+		// this connection gets; packet_sent and everything from inside the
+		// handshake/recovery/stream machinery is not captured.
+		if e := s.qlog.get(p.header.DCID); e != nil {
+			e.PacketReceived(transport.QlogPacketEvent{
+				PacketType: fmt.Sprintf("%v", p.header.Type),
+				Length:     len(p.data),
+			})
+		}
 		c.recvCh <- p
 	} else {
 		s.logger.Log(LevelDebug, "%s ignore unknown destination packet: %s", p.addr, &p.header)
@@ -111,6 +132,51 @@ func (s *Client) Connect(addr string) error {
 	return nil
 }
 
+// ConnectEarly does not yet send or receive 0-RTT data: it is groundwork for
+// that feature, not the feature itself. It establishes a new connection to
+// UDP network address addr the same way Connect does, except it requires a
+// TLS session cache to be configured, which is the actual precondition for a
+// resumed handshake to attempt 0-RTT.
+//
+// Giving applications a way to write 0-RTT data before the handshake
+// completes requires classifying writes against handshake confirmation state
+// (e.g. a Stream.WriteEarly path, or an automatic classification based on
+// Conn handshake-done state); none of that exists yet. Until it does,
+// ConnectEarly behaves identically to Connect, other than requiring the
+// session cache up front; it exists so callers can start depending on the
+// final signature now, not so they can send early data today.
+func (s *Client) ConnectEarly(addr string) (Conn, error) {
+	if s.config.TLS == nil || s.config.TLS.ClientSessionCache == nil {
+		return nil, fmt.Errorf("quic: ConnectEarly requires a TLS session cache")
+	}
+	c, err := s.newConn(addr)
+	if err != nil {
+		return nil, err
+	}
+	s.peersMu.Lock()
+	if s.closing {
+		s.peersMu.Unlock()
+		return nil, fmt.Errorf("client is closed")
+	}
+	if _, ok := s.peers[string(c.scid[:])]; ok {
+		s.peersMu.Unlock()
+		return nil, fmt.Errorf("connection id conflict scid=%x", c.scid)
+	}
+	s.peers[string(c.scid[:])] = c
+	s.peersMu.Unlock()
+	// Send initial packet; no 0-RTT packet is coalesced after it (see above).
+	p := newPacket()
+	defer freePacket(p)
+	if err = s.sendConn(c, p.buf[:maxDatagramSize]); err != nil {
+		s.peersMu.Lock()
+		delete(s.peers, string(c.scid[:]))
+		s.peersMu.Unlock()
+		return nil, fmt.Errorf("send %s: %v", c.addr, err)
+	}
+	go s.handleConn(c)
+	return c, nil
+}
+
 // Close closes all current establised connections and listening socket.
 func (s *Client) Close() error {
 	s.close(10 * time.Second)
@@ -120,6 +186,39 @@ func (s *Client) Close() error {
 	return nil
 }
 
+// retryVersion picks the highest version present in both versions and the
+// ones this client supports and restarts the handshake with it; it returns
+// an error if there is no overlap, since that means this client cannot
+// interoperate with the server at all.
+//
+// retryVersion has no caller yet: it is meant to run when a connection's
+// transport.Conn surfaces a received Version Negotiation packet, but nothing
+// in this package decodes one off the wire and turns it into that event, so
+// a VN packet from a server currently just goes unanswered. Until that
+// decode path exists, a Client never actually retries with a negotiated
+// version.
+//
+// The Client's *transport.Config is shared across every connection it
+// creates, so the picked version is applied to a per-connection copy rather
+// than to s.config itself; otherwise one connection's negotiated version
+// would leak into unrelated, possibly concurrent, Connect calls.
+func (s *Client) retryVersion(c *remoteConn, versions []uint32) error {
+	local := s.config.Versions
+	if len(local) == 0 {
+		local = transport.SupportedVersions()
+	}
+	picked := transport.PickVersion(local, versions)
+	if picked == 0 {
+		return fmt.Errorf("no compatible QUIC version with %s (offered %v)", c.addr, versions)
+	}
+	s.logger.Log(LevelInfo, "%s retry with version=%#x", c.addr, picked)
+	config := *s.config
+	config.Version = picked
+	var err error
+	c.conn, err = transport.Connect(c.scid[:], &config)
+	return err
+}
+
 func (s *Client) newConn(addr string) (*remoteConn, error) {
 	udpAddr, err := net.ResolveUDPAddr("udp", addr)
 	if err != nil {
@@ -129,9 +228,35 @@ func (s *Client) newConn(addr string) (*remoteConn, error) {
 	if err = s.rand(c.scid[:]); err != nil {
 		return nil, fmt.Errorf("generate connection id: %v", err)
 	}
-	c.conn, err = transport.Connect(c.scid[:], s.config)
+	config := s.config
+	if config.Version == 0 {
+		// Pick the highest version we support; a Version Negotiation packet
+		// from the server does not make this connection switch, since
+		// retryVersion above has no caller. Picked into a per-connection copy,
+		// not s.config itself, for the same reason retryVersion copies below:
+		// s.config is shared across every connection this Client creates, and
+		// concurrent first calls would otherwise race on the write.
+		versions := config.Versions
+		if len(versions) == 0 {
+			versions = transport.SupportedVersions()
+		}
+		cfg := *config
+		cfg.Version = versions[0]
+		config = &cfg
+	}
+	c.conn, err = transport.Connect(c.scid[:], config)
 	if err != nil {
 		return nil, err
 	}
+	if s.qlogDir != "" {
+		qlog, err := newFileQlogEmitter(s.qlogDir, c.scid[:])
+		if err != nil {
+			s.logger.Log(LevelError, "%s create qlog: %v", addr, err)
+		} else {
+			// transport.Conn has no SetQlogEmitter of its own; register under
+			// scid so Client.recv can look it up and feed it packet_received.
+			s.qlog.set(c.scid[:], qlog)
+		}
+	}
 	return c, nil
 }
@@ -0,0 +1,48 @@
+package quic
+
+import (
+	"context"
+	"errors"
+)
+
+// defaultDatagramQueueSize is the number of received datagrams buffered per
+// connection before the oldest is dropped.
+//
+// It has no effect yet: SendDatagram/ReceiveDatagram/Stats below cannot be
+// backed by it, because remoteConn has no datagramCh, closedCh or
+// datagramQueue field in this source tree to hold the state they need, and
+// transport.Conn has no SendDatagram/ReceiveDatagram/DatagramStats method
+// for them to call either. No commit in this series touches the files that
+// declare remoteConn or transport.Conn, so referencing those fields and
+// methods was a compile break, not just an unwired scaffold; the methods
+// were reverted to reporting errNoDatagramSupport instead of dereferencing
+// state that isn't there.
+const defaultDatagramQueueSize = 32
+
+// Stats holds counters about a connection that are cheap to expose and useful
+// for monitoring, such as DATAGRAM frames dropped due to a full receive queue.
+type Stats struct {
+	DatagramDropped uint64
+}
+
+var errNoDatagramSupport = errors.New("quic: remoteConn has no datagram support in this build")
+
+// SendDatagram sends b unreliably as a single QUIC DATAGRAM frame (RFC 9221).
+// See the comment on defaultDatagramQueueSize above for why it cannot do
+// that yet.
+func (c *remoteConn) SendDatagram(b []byte) error {
+	return errNoDatagramSupport
+}
+
+// ReceiveDatagram would block until a datagram is available, ctx is done, or
+// the connection is closed, whichever happens first. See the comment on
+// defaultDatagramQueueSize above for why it cannot do that yet.
+func (c *remoteConn) ReceiveDatagram(ctx context.Context) ([]byte, error) {
+	return nil, errNoDatagramSupport
+}
+
+// Stats returns a snapshot of counters for this connection. See the comment
+// on defaultDatagramQueueSize above for why DatagramDropped is always zero.
+func (c *remoteConn) Stats() Stats {
+	return Stats{}
+}
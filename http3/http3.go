@@ -0,0 +1,148 @@
+// Package http3 implements HTTP/3 (RFC 9114) on top of the github.com/goburrow/quic
+// Client and Server primitives.
+//
+// It provides Server, which serves an http.Handler over a quic.Server, and
+// RoundTripper, which implements http.RoundTripper over a quic.Client so it can be
+// plugged directly into an http.Client.
+package http3
+
+import "errors"
+
+// errShortBuffer is returned by readVarint when b does not hold a complete
+// variable-length integer.
+var errShortBuffer = errors.New("http3: short buffer")
+
+// Stream types for unidirectional streams, as defined in RFC 9114 section 6.2
+// and RFC 9204 (QPACK) section 4.2.
+const (
+	streamTypeControl      = 0x00
+	streamTypePush         = 0x01
+	streamTypeQPACKEncoder = 0x02
+	streamTypeQPACKDecoder = 0x03
+)
+
+// Frame types, as defined in RFC 9114 section 7.2.
+const (
+	frameTypeData        = 0x00
+	frameTypeHeaders     = 0x01
+	frameTypeCancelPush  = 0x03
+	frameTypeSettings    = 0x04
+	frameTypePushPromise = 0x05
+	frameTypeGoaway      = 0x07
+	frameTypeMaxPushID   = 0x0d
+)
+
+// maxFrameSize bounds the length field readFrame will allocate for, so a peer
+// cannot force an arbitrarily large allocation by sending a HEADERS or DATA
+// frame header with a huge declared length and little or no payload behind
+// it. It is well above any field section or DATA chunk this implementation
+// produces itself.
+const maxFrameSize = 1 << 20 // 1 MiB
+
+// Settings identifiers, as defined in RFC 9114 section 7.2.4.1.
+const (
+	settingQPACKMaxTableCapacity = 0x01
+	settingMaxFieldSectionSize   = 0x06
+	settingQPACKBlockedStreams   = 0x07
+)
+
+// settings holds the SETTINGS values exchanged on the control stream at the
+// start of a connection.
+type settings struct {
+	qpackMaxTableCapacity uint64
+	maxFieldSectionSize   uint64
+	qpackBlockedStreams   uint64
+}
+
+// defaultSettings returns the SETTINGS this implementation advertises.
+func defaultSettings() settings {
+	return settings{
+		qpackMaxTableCapacity: 0, // dynamic table disabled by default
+		maxFieldSectionSize:   0, // unlimited
+		qpackBlockedStreams:   0,
+	}
+}
+
+// appendVarint appends a QUIC variable-length integer to b.
+func appendVarint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 63:
+		return append(b, byte(v))
+	case v <= 16383:
+		return append(b, byte(v>>8)|0x40, byte(v))
+	case v <= 1073741823:
+		return append(b, byte(v>>24)|0x80, byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(b, byte(v>>56)|0xc0, byte(v>>48), byte(v>>40), byte(v>>32),
+			byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// appendFrame appends a frame header (type, length) followed by payload to b.
+func appendFrame(b []byte, typ uint64, payload []byte) []byte {
+	b = appendVarint(b, typ)
+	b = appendVarint(b, uint64(len(payload)))
+	return append(b, payload...)
+}
+
+// appendSettingsFrame encodes s as a SETTINGS frame payload wrapped in its frame header.
+func appendSettingsFrame(b []byte, s settings) []byte {
+	var payload []byte
+	if s.qpackMaxTableCapacity > 0 {
+		payload = appendVarint(payload, settingQPACKMaxTableCapacity)
+		payload = appendVarint(payload, s.qpackMaxTableCapacity)
+	}
+	if s.maxFieldSectionSize > 0 {
+		payload = appendVarint(payload, settingMaxFieldSectionSize)
+		payload = appendVarint(payload, s.maxFieldSectionSize)
+	}
+	if s.qpackBlockedStreams > 0 {
+		payload = appendVarint(payload, settingQPACKBlockedStreams)
+		payload = appendVarint(payload, s.qpackBlockedStreams)
+	}
+	return appendFrame(b, frameTypeSettings, payload)
+}
+
+// readVarint decodes a QUIC variable-length integer from the start of b,
+// returning the value and the number of bytes consumed.
+func readVarint(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errShortBuffer
+	}
+	n := 1 << (b[0] >> 6)
+	if len(b) < n {
+		return 0, 0, errShortBuffer
+	}
+	v := uint64(b[0] & 0x3f)
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, n, nil
+}
+
+// decodeSettingsFrame parses a SETTINGS frame payload, ignoring any
+// identifier it does not recognize, as RFC 9114 section 7.2.4 requires.
+func decodeSettingsFrame(payload []byte) settings {
+	var s settings
+	for len(payload) > 0 {
+		id, n, err := readVarint(payload)
+		if err != nil {
+			return s
+		}
+		payload = payload[n:]
+		value, n, err := readVarint(payload)
+		if err != nil {
+			return s
+		}
+		payload = payload[n:]
+		switch id {
+		case settingQPACKMaxTableCapacity:
+			s.qpackMaxTableCapacity = value
+		case settingMaxFieldSectionSize:
+			s.maxFieldSectionSize = value
+		case settingQPACKBlockedStreams:
+			s.qpackBlockedStreams = value
+		}
+	}
+	return s
+}
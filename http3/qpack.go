@@ -0,0 +1,200 @@
+package http3
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// qpackStaticEntry is one row of the QPACK static table (RFC 9204 Appendix A).
+type qpackStaticEntry struct {
+	name  string
+	value string
+}
+
+// qpackStaticTable holds the subset of the QPACK static table this codec relies on.
+// It is not the full 99-entry table; entries not listed here are always encoded
+// as literals.
+var qpackStaticTable = []qpackStaticEntry{
+	{":authority", ""},
+	{":path", "/"},
+	{":method", "GET"},
+	{":method", "POST"},
+	{":scheme", "https"},
+	{":status", "200"},
+	{":status", "404"},
+	{"content-type", "text/plain"},
+}
+
+// qpackStaticTableIndex holds the RFC 9204 Appendix A index of each entry of
+// qpackStaticTable, in the same order; it is the index an Indexed Field Line
+// carries on the wire.
+var qpackStaticTableIndex = []uint64{0, 1, 17, 20, 23, 25, 27, 53}
+
+// qpackEncoder produces a QPACK-encoded field section for a set of headers.
+// This implementation only uses the static table and literal fields; it never
+// inserts into the dynamic table, so it never needs to block a stream on
+// decoder instructions (dynamic table capacity is always negotiated as 0).
+type qpackEncoder struct{}
+
+// newQPACKEncoder creates an encoder with an empty (disabled) dynamic table.
+func newQPACKEncoder() *qpackEncoder {
+	return &qpackEncoder{}
+}
+
+// Encode appends the field section prefix and the encoded fields to b.
+func (e *qpackEncoder) Encode(b []byte, header http.Header, pseudo []qpackStaticEntry) []byte {
+	// Field section prefix: Required Insert Count (0) and Delta Base (0), both
+	// zero since we never reference the dynamic table.
+	b = append(b, 0x00, 0x00)
+	for _, p := range pseudo {
+		b = e.encodeField(b, p.name, p.value)
+	}
+	for name, values := range header {
+		for _, v := range values {
+			b = e.encodeField(b, name, v)
+		}
+	}
+	return b
+}
+
+func (e *qpackEncoder) encodeField(b []byte, name, value string) []byte {
+	if idx, ok := staticIndex(name, value); ok {
+		// Indexed Field Line, static table: 1Txxxxxx
+		return appendQPACKVarint(b, 0xc0, 6, uint64(idx))
+	}
+	// Literal Field Line With Literal Name (RFC 9204 section 4.5.6): 001NHxxx,
+	// where N is Never Indexed and H is Huffman (always 0 here, both name and
+	// value are sent as plain ASCII), leaving a 3-bit length prefix.
+	b = appendQPACKVarint(b, 0x20, 3, uint64(len(name)))
+	b = append(b, name...)
+	b = appendQPACKVarint(b, 0x00, 7, uint64(len(value)))
+	return append(b, value...)
+}
+
+func staticIndex(name, value string) (int, bool) {
+	for i, e := range qpackStaticTable {
+		if e.name == name && e.value == value {
+			return int(qpackStaticTableIndex[i]), true
+		}
+	}
+	return 0, false
+}
+
+// staticEntry returns the qpackStaticTable entry whose RFC 9204 static table
+// index is idx, if this codec's subset has it.
+func staticEntry(idx uint64) (qpackStaticEntry, bool) {
+	for i, v := range qpackStaticTableIndex {
+		if v == idx {
+			return qpackStaticTable[i], true
+		}
+	}
+	return qpackStaticEntry{}, false
+}
+
+// appendQPACKVarint appends v as a prefixed integer (RFC 9204 section 4.1.1) using
+// the given first-byte prefix bits and the number of low bits available in the
+// first byte.
+func appendQPACKVarint(b []byte, prefixBits byte, n uint, v uint64) []byte {
+	max := uint64(1<<n) - 1
+	if v < max {
+		return append(b, prefixBits|byte(v))
+	}
+	b = append(b, prefixBits|byte(max))
+	v -= max
+	for v >= 0x80 {
+		b = append(b, byte(v&0x7f)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// qpackDecoder decodes a QPACK field section encoded by qpackEncoder.
+type qpackDecoder struct{}
+
+func newQPACKDecoder() *qpackDecoder {
+	return &qpackDecoder{}
+}
+
+// Decode parses the field section in b, splitting it into pseudo (names
+// starting with ':', e.g. ":method" or ":status") and header (everything
+// else). Callers that build an http.Request or http.Response must not
+// assign pseudo directly to Request.Header/Response.Header: pseudo-headers
+// are not ordinary HTTP header fields.
+func (d *qpackDecoder) Decode(b []byte) (header, pseudo http.Header, err error) {
+	if len(b) < 2 {
+		return nil, nil, errors.New("http3: qpack: field section too short")
+	}
+	b = b[2:] // skip Required Insert Count and Delta Base
+	header = make(http.Header)
+	pseudo = make(http.Header)
+	add := func(name, value string) {
+		if strings.HasPrefix(name, ":") {
+			pseudo.Add(name, value)
+		} else {
+			header.Add(name, value)
+		}
+	}
+	for len(b) > 0 {
+		switch {
+		case b[0]&0xc0 == 0xc0:
+			idx, rest, err := readQPACKVarint(b, 0xc0, 6)
+			if err != nil {
+				return nil, nil, err
+			}
+			e, ok := staticEntry(idx)
+			if !ok {
+				return nil, nil, errors.New("http3: qpack: static table index not supported")
+			}
+			add(e.name, e.value)
+			b = rest
+		case b[0]&0xe0 == 0x20:
+			name, rest, err := readQPACKString(b, 0x20, 3)
+			if err != nil {
+				return nil, nil, err
+			}
+			value, rest2, err := readQPACKString(rest, 0x00, 7)
+			if err != nil {
+				return nil, nil, err
+			}
+			add(name, value)
+			b = rest2
+		default:
+			return nil, nil, errors.New("http3: qpack: unsupported field line representation")
+		}
+	}
+	return header, pseudo, nil
+}
+
+func readQPACKVarint(b []byte, prefixMask byte, n uint) (uint64, []byte, error) {
+	max := uint64(1<<n) - 1
+	v := uint64(b[0] & byte(max))
+	b = b[1:]
+	if v < max {
+		return v, b, nil
+	}
+	var shift uint
+	for {
+		if len(b) == 0 {
+			return 0, nil, errors.New("http3: qpack: truncated varint")
+		}
+		v += uint64(b[0]&0x7f) << shift
+		more := b[0]&0x80 != 0
+		b = b[1:]
+		if !more {
+			return v, b, nil
+		}
+		shift += 7
+	}
+}
+
+func readQPACKString(b []byte, prefixMask byte, n uint) (string, []byte, error) {
+	l, rest, err := readQPACKVarint(b, prefixMask, n)
+	if err != nil {
+		return "", nil, err
+	}
+	if uint64(len(rest)) < l {
+		return "", nil, errors.New("http3: qpack: truncated string")
+	}
+	return string(rest[:l]), rest[l:], nil
+}
@@ -0,0 +1,115 @@
+package http3
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestQPACKStaticTableRoundTrip(t *testing.T) {
+	enc := newQPACKEncoder()
+	dec := newQPACKDecoder()
+
+	pseudo := []qpackStaticEntry{
+		{":method", "GET"},
+		{":scheme", "https"},
+		{":status", "200"},
+	}
+	header := http.Header{"Content-Type": {"text/plain"}}
+
+	buf := enc.Encode(nil, header, pseudo)
+	got, gotPseudo, err := dec.Decode(buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := http.Header{"Content-Type": {"text/plain"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Decode header = %#v, want %#v", got, want)
+	}
+	wantPseudo := http.Header{
+		":method": {"GET"},
+		":scheme": {"https"},
+		":status": {"200"},
+	}
+	if !reflect.DeepEqual(gotPseudo, wantPseudo) {
+		t.Fatalf("Decode pseudo = %#v, want %#v", gotPseudo, wantPseudo)
+	}
+}
+
+func TestQPACKLiteralFieldsRoundTrip(t *testing.T) {
+	enc := newQPACKEncoder()
+	dec := newQPACKDecoder()
+
+	pseudo := []qpackStaticEntry{
+		{":authority", "example.com"},
+		{":path", "/not/in/static/table"},
+	}
+	header := http.Header{"X-Custom": {"value-not-in-static-table"}}
+
+	buf := enc.Encode(nil, header, pseudo)
+	got, gotPseudo, err := dec.Decode(buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := http.Header{"X-Custom": {"value-not-in-static-table"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Decode header = %#v, want %#v", got, want)
+	}
+	wantPseudo := http.Header{
+		":authority": {"example.com"},
+		":path":      {"/not/in/static/table"},
+	}
+	if !reflect.DeepEqual(gotPseudo, wantPseudo) {
+		t.Fatalf("Decode pseudo = %#v, want %#v", gotPseudo, wantPseudo)
+	}
+}
+
+func TestQPACKLiteralFieldLineBitLayout(t *testing.T) {
+	// RFC 9204 section 4.5.6: Literal Field Line With Literal Name is
+	// "0 0 1 N H nnn", a 3-bit name-length prefix, not the encoder's name
+	// length itself folded over the H bit.
+	enc := newQPACKEncoder()
+	buf := enc.Encode(nil, nil, []qpackStaticEntry{{":path", "/abcdefg"}})
+	buf = buf[2:] // skip Required Insert Count / Delta Base
+	if buf[0]&0xe0 != 0x20 {
+		t.Fatalf("first byte %#x does not start a literal field line with literal name", buf[0])
+	}
+	if n := buf[0] & 0x07; n != 5 {
+		t.Fatalf("name length prefix = %d, want 5 (len(\":path\") with a 3-bit prefix)", n)
+	}
+}
+
+func TestQPACKVarintRoundTrip(t *testing.T) {
+	for _, v := range []uint64{0, 1, 62, 63, 64, 1000, 16383, 16384, 1 << 20} {
+		b := appendQPACKVarint(nil, 0x20, 4, v)
+		got, rest, err := readQPACKVarint(b, 0x20, 4)
+		if err != nil {
+			t.Fatalf("readQPACKVarint(%d): %v", v, err)
+		}
+		if got != v {
+			t.Errorf("readQPACKVarint round trip = %d, want %d", got, v)
+		}
+		if len(rest) != 0 {
+			t.Errorf("readQPACKVarint(%d) left %d unread bytes", v, len(rest))
+		}
+	}
+}
+
+func TestDecodeSettingsFrame(t *testing.T) {
+	want := settings{qpackMaxTableCapacity: 4096, maxFieldSectionSize: 65536, qpackBlockedStreams: 16}
+	buf := appendSettingsFrame(nil, want)
+	// Strip the frame header (type, length) readFrame would normally consume.
+	_, n1, err := readVarint(buf)
+	if err != nil {
+		t.Fatalf("readVarint type: %v", err)
+	}
+	_, n2, err := readVarint(buf[n1:])
+	if err != nil {
+		t.Fatalf("readVarint length: %v", err)
+	}
+	payload := buf[n1+n2:]
+	got := decodeSettingsFrame(payload)
+	if got != want {
+		t.Fatalf("decodeSettingsFrame = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+package http3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/goburrow/quic"
+	"github.com/goburrow/quic/transport"
+)
+
+// RoundTripper implements http.RoundTripper over a quic.Client, allowing an
+// http.Client to send requests over HTTP/3. Each distinct host:port opens its
+// own QUIC connection, which is reused for subsequent requests.
+type RoundTripper struct {
+	// TLSConfig is not read by RoundTrip: new connections use the config
+	// passed to NewRoundTripper, whose TLS.NextProtos is set to {"h3"}.
+	TLSConfig *transport.Config
+
+	client  *quic.Client
+	handler *clientConnHandler
+}
+
+const alpnH3 = "h3"
+
+// NewRoundTripper creates a RoundTripper using config as the base connection
+// configuration. config.TLS.NextProtos is overwritten with {"h3"}.
+func NewRoundTripper(config *transport.Config) *RoundTripper {
+	if config.TLS != nil {
+		config.TLS.NextProtos = []string{alpnH3}
+	}
+	h := newClientConnHandler()
+	rt := &RoundTripper{handler: h}
+	rt.client = quic.NewClient(config)
+	rt.client.SetHandler(h)
+	return rt
+}
+
+// RoundTrip opens a request stream on the QUIC connection to req.URL.Host
+// (establishing one first if none is open yet), writes req as a HEADERS
+// frame, and blocks until the matching response HEADERS frame arrives or
+// req.Context() is done.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("http3: unsupported scheme %q", req.URL.Scheme)
+	}
+	ctx := req.Context()
+	c, err := rt.handler.getConn(rt.client, req.URL.Host, ctx)
+	if err != nil {
+		return nil, err
+	}
+	cn := rt.handler.connState(c)
+	st, err := sendRequest(c, cn.encoder, req)
+	if err != nil {
+		return nil, err
+	}
+	respCh := cn.registerPending(st.ID())
+	select {
+	case resp, ok := <-respCh:
+		if !ok {
+			return nil, fmt.Errorf("http3: stream %d closed before a response was received", st.ID())
+		}
+		return resp, nil
+	case <-ctx.Done():
+		cn.cancelPending(st.ID())
+		return nil, ctx.Err()
+	}
+}
+
+// clientConnHandler adapts quic.Handler events into pending HTTP/3 responses,
+// tracking one *conn per established QUIC connection and handing freshly
+// connected quic.Conn values to whichever RoundTrip call is waiting for them.
+//
+// Connections are cached in conns, keyed by the resolved UDP address that
+// Client.Connect dials and that c.RemoteAddr() later reports for the
+// resulting quic.Conn, so a RoundTrip call and the Serve callback reporting
+// its connection agree on the same key regardless of what host string (a
+// domain name, possibly) the caller passed in.
+type clientConnHandler struct {
+	mu      sync.Mutex
+	conns   map[string]quic.Conn
+	waiting map[string][]chan quic.Conn
+}
+
+func newClientConnHandler() *clientConnHandler {
+	return &clientConnHandler{
+		conns:   make(map[string]quic.Conn),
+		waiting: make(map[string][]chan quic.Conn),
+	}
+}
+
+// getConn returns the established quic.Conn for host, dialing one with
+// client.Connect and waiting for it if none is cached yet. Concurrent calls
+// for the same host that race while a connection is still being established
+// share the same in-flight Connect rather than each starting their own.
+func (h *clientConnHandler) getConn(client *quic.Client, host string, ctx context.Context) (quic.Conn, error) {
+	addr, err := net.ResolveUDPAddr("udp", host)
+	if err != nil {
+		return nil, err
+	}
+	key := addr.String()
+
+	h.mu.Lock()
+	if c, ok := h.conns[key]; ok {
+		h.mu.Unlock()
+		return c, nil
+	}
+	dial := len(h.waiting[key]) == 0
+	ready := make(chan quic.Conn, 1)
+	h.waiting[key] = append(h.waiting[key], ready)
+	h.mu.Unlock()
+
+	if dial {
+		if err := client.Connect(host); err != nil {
+			h.cancelWait(key, ready)
+			return nil, err
+		}
+	}
+	select {
+	case c := <-ready:
+		return c, nil
+	case <-ctx.Done():
+		h.cancelWait(key, ready)
+		return nil, ctx.Err()
+	}
+}
+
+// cancelWait removes ch from the waiters for key, e.g. after Connect failed
+// synchronously or the caller's context was done before a connection arrived.
+func (h *clientConnHandler) cancelWait(key string, ch chan quic.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	waiters := h.waiting[key]
+	for i, w := range waiters {
+		if w == ch {
+			h.waiting[key] = append(waiters[:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (h *clientConnHandler) Serve(c quic.Conn, events []interface{}) {
+	key := c.RemoteAddr().String()
+	h.mu.Lock()
+	h.conns[key] = c
+	waiters := h.waiting[key]
+	delete(h.waiting, key)
+	h.mu.Unlock()
+	for _, ch := range waiters {
+		ch <- c
+	}
+
+	cn := h.connState(c)
+	for _, e := range events {
+		switch e := e.(type) {
+		case transport.StreamRecvEvent:
+			respCh, ok := cn.takePending(e.StreamID)
+			if !ok {
+				continue
+			}
+			st := c.Stream(e.StreamID)
+			if st == nil {
+				close(respCh)
+				continue
+			}
+			resp, err := readResponse(st)
+			if err != nil {
+				close(respCh)
+				continue
+			}
+			respCh <- resp
+		}
+	}
+}
+
+// connState looks up (or lazily creates) the HTTP/3 state attached to c.
+func (h *clientConnHandler) connState(c quic.Conn) *conn {
+	cn, _ := c.UserData().(*conn)
+	if cn == nil {
+		cn = newConn(c, nil)
+		c.SetUserData(cn)
+	}
+	return cn
+}
+
+// registerPending records that a response is expected on streamID and
+// returns the channel it will arrive on.
+func (cn *conn) registerPending(streamID uint64) chan *http.Response {
+	ch := make(chan *http.Response, 1)
+	cn.pendingMu.Lock()
+	if cn.pending == nil {
+		cn.pending = make(map[uint64]chan *http.Response)
+	}
+	cn.pending[streamID] = ch
+	cn.pendingMu.Unlock()
+	return ch
+}
+
+// takePending removes and returns the channel registered for streamID, if any.
+func (cn *conn) takePending(streamID uint64) (chan *http.Response, bool) {
+	cn.pendingMu.Lock()
+	defer cn.pendingMu.Unlock()
+	ch, ok := cn.pending[streamID]
+	if ok {
+		delete(cn.pending, streamID)
+	}
+	return ch, ok
+}
+
+// cancelPending drops a registration abandoned because its RoundTrip caller's
+// context was done before a response arrived.
+func (cn *conn) cancelPending(streamID uint64) {
+	cn.pendingMu.Lock()
+	delete(cn.pending, streamID)
+	cn.pendingMu.Unlock()
+}
+
+// sendRequest writes req as a HEADERS (+ optional DATA) frame on a freshly
+// opened bidirectional stream and returns the stream used, so the caller can
+// wait for and read the matching response.
+func sendRequest(c quic.Conn, enc *qpackEncoder, req *http.Request) (quic.Stream, error) {
+	st := c.StreamOpenBidi()
+	pseudo := []qpackStaticEntry{
+		{":method", req.Method},
+		{":scheme", "https"},
+		{":authority", req.URL.Host},
+		{":path", req.URL.RequestURI()},
+	}
+	var buf []byte
+	buf = enc.Encode(buf, req.Header, pseudo)
+	if _, err := st.Write(appendFrame(nil, frameTypeHeaders, buf)); err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			if _, err := st.Write(appendFrame(nil, frameTypeData, body)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return st, nil
+}
@@ -0,0 +1,231 @@
+package http3
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/goburrow/quic"
+	"github.com/goburrow/quic/transport"
+)
+
+// Server serves HTTP/3 requests to a Handler over a quic.Server.
+//
+// A Server must not be copied after first use.
+type Server struct {
+	// Handler invokes the given handler for every request. http.DefaultServeMux
+	// is used when Handler is nil.
+	Handler http.Handler
+
+	// Addr is advertised in the Alt-Svc header value returned by SetAltSvc so that
+	// HTTP/1.1 and HTTP/2 clients can discover this HTTP/3 endpoint.
+	Addr string
+
+	quic *quic.Server
+}
+
+// NewServer creates a new HTTP/3 server backed by config. config should set
+// Params.InitialMaxStreamsUni to at least 3 so the control and QPACK streams
+// can always be opened.
+func NewServer(config *transport.Config) *Server {
+	s := &Server{}
+	s.quic = quic.NewServer(config)
+	s.quic.SetHandler(&serverConnHandler{server: s})
+	return s
+}
+
+// SetAltSvc returns the value of the Alt-Svc header HTTP/1.1 and HTTP/2
+// servers should write to advertise this server, e.g. `h3=":443"; ma=3600`.
+func (s *Server) SetAltSvc(maxAge int) string {
+	return fmt.Sprintf(`h3=%q; ma=%d`, s.Addr, maxAge)
+}
+
+// ListenAndServe starts listening on addr and serves incoming HTTP/3 requests.
+func (s *Server) ListenAndServe(addr string) error {
+	s.Addr = addr
+	return s.quic.ListenAndServe(addr)
+}
+
+// Close closes the underlying QUIC server.
+func (s *Server) Close() error {
+	return s.quic.Close()
+}
+
+// serverConnHandler adapts quic.Handler events to per-connection HTTP/3 state.
+type serverConnHandler struct {
+	server *Server
+}
+
+func (h *serverConnHandler) Serve(c quic.Conn, events []interface{}) {
+	conn := h.connState(c)
+	for _, e := range events {
+		switch e := e.(type) {
+		case transport.StreamRecvEvent:
+			conn.handleStream(c, e.StreamID)
+		}
+	}
+}
+
+// connState looks up (or lazily creates) the HTTP/3 state attached to c.
+// The control and QPACK streams are opened the first time a connection is seen.
+func (h *serverConnHandler) connState(c quic.Conn) *conn {
+	// Connection-scoped state is tracked by the caller (quic.Conn implementations
+	// keep a side-table keyed by connection); see roundtrip.go for the client-side
+	// equivalent. The handler only needs request routing, so state is created
+	// on demand per call and cached by the connection.
+	st, _ := c.UserData().(*conn)
+	if st == nil {
+		handler := h.server.Handler
+		if handler == nil {
+			handler = http.DefaultServeMux
+		}
+		st = newConn(c, handler)
+		c.SetUserData(st)
+		st.openControlStream(c)
+	}
+	return st
+}
+
+// conn holds per-QUIC-connection HTTP/3 state: the control stream, the QPACK
+// encoder/decoder streams, and in-flight request streams.
+type conn struct {
+	handler  http.Handler
+	encoder  *qpackEncoder
+	decoder  *qpackDecoder
+	settings settings // settings this side advertised
+
+	peerSettings settings // settings read from the peer's control stream
+	uniMu        sync.Mutex
+	uniTypes     map[uint64]uint64 // uni stream ID -> stream type, once its first byte has been read
+
+	pendingMu sync.Mutex
+	pending   map[uint64]chan *http.Response // request stream ID -> response waiter (client side only)
+}
+
+// newConn creates per-connection HTTP/3 state. handler is nil on the client
+// side, which never serves incoming requests.
+func newConn(c quic.Conn, handler http.Handler) *conn {
+	return &conn{
+		handler:  handler,
+		encoder:  newQPACKEncoder(),
+		decoder:  newQPACKDecoder(),
+		settings: defaultSettings(),
+	}
+}
+
+// openControlStream opens the unidirectional control stream and the QPACK
+// encoder/decoder streams, then writes the initial SETTINGS frame.
+func (cn *conn) openControlStream(c quic.Conn) {
+	control := c.StreamOpenUni()
+	buf := appendVarint(nil, streamTypeControl)
+	buf = appendSettingsFrame(buf, cn.settings)
+	control.Write(buf)
+
+	encoder := c.StreamOpenUni()
+	encoder.Write(appendVarint(nil, streamTypeQPACKEncoder))
+
+	decoder := c.StreamOpenUni()
+	decoder.Write(appendVarint(nil, streamTypeQPACKDecoder))
+}
+
+// The low 2 bits of a QUIC stream ID encode its initiator and directionality
+// (RFC 9000 section 2.1): 0x0 client-initiated bidi, 0x2 client-initiated uni.
+const (
+	streamClientBidi = 0x0
+	streamClientUni  = 0x2
+)
+
+// handleStream dispatches streamID to request handling or unidirectional
+// control/QPACK-stream handling, based on the stream ID's initiator bits; it
+// ignores server-initiated stream IDs, since this side never expects the
+// peer to open one.
+func (cn *conn) handleStream(c quic.Conn, streamID uint64) {
+	switch streamID & 0x3 {
+	case streamClientBidi:
+		cn.handleRequestStream(c, streamID)
+	case streamClientUni:
+		cn.handleUniStream(c, streamID)
+	}
+}
+
+// handleRequestStream reads a request off a bidirectional request stream,
+// dispatches it to the Handler, and writes the response back on the same stream.
+func (cn *conn) handleRequestStream(c quic.Conn, streamID uint64) {
+	if cn.handler == nil {
+		// Client side: this connection never receives requests.
+		return
+	}
+	st := c.Stream(streamID)
+	if st == nil {
+		return
+	}
+	req, err := cn.readRequest(st)
+	if err != nil {
+		st.Close()
+		return
+	}
+	rw := &responseWriter{stream: st, encoder: cn.encoder, header: make(http.Header)}
+	cn.handler.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+// handleUniStream reads the stream type of a client-initiated unidirectional
+// stream the first time data arrives on it, then routes it to control-stream
+// or QPACK-stream handling. The QPACK encoder/decoder streams carry nothing
+// to act on here since this implementation never grows the dynamic table
+// (qpackMaxTableCapacity is always negotiated as 0).
+func (cn *conn) handleUniStream(c quic.Conn, streamID uint64) {
+	st := c.Stream(streamID)
+	if st == nil {
+		return
+	}
+	cn.uniMu.Lock()
+	typ, known := cn.uniTypes[streamID]
+	cn.uniMu.Unlock()
+	if !known {
+		t, err := readVarintFrom(st)
+		if err != nil {
+			return
+		}
+		typ = t
+		cn.uniMu.Lock()
+		if cn.uniTypes == nil {
+			cn.uniTypes = make(map[uint64]uint64)
+		}
+		cn.uniTypes[streamID] = typ
+		cn.uniMu.Unlock()
+	}
+	if typ == streamTypeControl {
+		cn.readControlStream(st)
+	}
+}
+
+// readControlStream reads the SETTINGS frame that RFC 9114 section 7.2.4.1
+// requires to be the first frame on the peer's control stream, and records it
+// as peerSettings.
+func (cn *conn) readControlStream(st quic.Stream) {
+	frame, err := readFrame(st, frameTypeSettings)
+	if err != nil {
+		return
+	}
+	cn.peerSettings = decodeSettingsFrame(frame)
+}
+
+// readRequest parses the HEADERS frame at the start of st into an *http.Request.
+func (cn *conn) readRequest(st quic.Stream) (*http.Request, error) {
+	frame, err := readFrame(st, frameTypeHeaders)
+	if err != nil {
+		return nil, err
+	}
+	header, pseudo, err := cn.decoder.Decode(frame)
+	if err != nil {
+		return nil, err
+	}
+	req := &http.Request{
+		Method: pseudo.Get(":method"),
+		Header: header,
+		Body:   &streamBody{stream: st},
+	}
+	req.URL, err = parseRequestURI(pseudo.Get(":scheme"), pseudo.Get(":authority"), pseudo.Get(":path"))
+	return req, err
+}
@@ -0,0 +1,166 @@
+package http3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/goburrow/quic"
+)
+
+// readFrame reads the next frame from st and returns its payload, failing if
+// the frame type does not match want.
+func readFrame(st quic.Stream, want uint64) ([]byte, error) {
+	typ, length, err := readFrameHeader(st)
+	if err != nil {
+		return nil, err
+	}
+	if typ != want {
+		return nil, errors.New("http3: unexpected frame type")
+	}
+	if length > maxFrameSize {
+		return nil, fmt.Errorf("http3: frame length %d exceeds %d byte maximum", length, maxFrameSize)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(st, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// readFrameHeader reads a varint frame type and length from st one byte at a
+// time, as required since frames are not necessarily aligned to stream reads.
+func readFrameHeader(st quic.Stream) (typ, length uint64, err error) {
+	typ, err = readVarintFrom(st)
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err = readVarintFrom(st)
+	if err != nil {
+		return 0, 0, err
+	}
+	return typ, length, nil
+}
+
+func readVarintFrom(r io.Reader) (uint64, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	prefix := b[0] >> 6
+	v := uint64(b[0] & 0x3f)
+	n := 1<<prefix - 1
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		v = v<<8 | uint64(b[0])
+	}
+	return v, nil
+}
+
+// streamBody adapts a quic.Stream to an io.ReadCloser suitable for
+// http.Request.Body and http.Response.Body. DATA frame boundaries are
+// transparent to the caller; only the payload bytes are returned.
+type streamBody struct {
+	stream  quic.Stream
+	pending []byte
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		payload, err := readFrame(b.stream, frameTypeData)
+		if err != nil {
+			return 0, err
+		}
+		b.pending = payload
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func (b *streamBody) Close() error {
+	return b.stream.Close()
+}
+
+// responseWriter implements http.ResponseWriter over a quic.Stream, encoding
+// the status line and headers as a QPACK HEADERS frame followed by the body
+// as one or more DATA frames.
+type responseWriter struct {
+	stream      quic.Stream
+	encoder     *qpackEncoder
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if _, err := w.stream.Write(appendFrame(nil, frameTypeData, p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	pseudo := []qpackStaticEntry{{":status", statusText(status)}}
+	buf := w.encoder.Encode(nil, w.header, pseudo)
+	w.stream.Write(appendFrame(nil, frameTypeHeaders, buf))
+}
+
+func (w *responseWriter) finish() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.stream.Close()
+}
+
+// statusText returns the ASCII digits of status, as RFC 9114 section 4.3.2
+// requires for the ":status" pseudo-header value (unlike HTTP/1.1's status
+// line, there is no reason phrase).
+func statusText(status int) string {
+	return strconv.Itoa(status)
+}
+
+// readResponse parses the HEADERS frame at the start of st into an *http.Response.
+func readResponse(st quic.Stream) (*http.Response, error) {
+	dec := newQPACKDecoder()
+	frame, err := readFrame(st, frameTypeHeaders)
+	if err != nil {
+		return nil, err
+	}
+	header, pseudo, err := dec.Decode(frame)
+	if err != nil {
+		return nil, err
+	}
+	status, err := strconv.Atoi(pseudo.Get(":status"))
+	if err != nil {
+		return nil, fmt.Errorf("http3: invalid :status %q: %v", pseudo.Get(":status"), err)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:      "HTTP/3.0",
+		ProtoMajor: 3,
+		Header:     header,
+		Body:       &streamBody{stream: st},
+	}, nil
+}
+
+// parseRequestURI reconstructs the request URL from HTTP/3 pseudo-headers.
+func parseRequestURI(scheme, authority, path string) (*url.URL, error) {
+	return url.Parse(scheme + "://" + authority + path)
+}
@@ -0,0 +1,36 @@
+package quic
+
+import "net"
+
+// Migration in this file is scaffolding, not a working implementation: it
+// detects and logs a possible migration, but cannot validate or switch to
+// the new path.
+//
+// A real implementation needs beginPathValidation/handlePathResponse/
+// confirmPathValidation methods guarded by an addrMu lock, plus
+// pendingPath, pathValidator and pathChangeFn fields to hold the state
+// between them, exactly as a previous revision of this file had them. That
+// revision did not build: remoteConn is declared in a file outside this
+// source tree, and no commit in this series touches that declaration, so
+// those fields were never actually on the struct. Rather than leave
+// call sites referencing fields that do not exist, this file was reverted
+// to the subset of remoteConn's surface (addr, scid) that the rest of the
+// package already relies on. issueCID/retireCID in cids.go are affected the
+// same way; see the comment there.
+//
+// Restoring real migration support means adding pendingPath, pathValidator
+// and pathChangeFn to remoteConn's actual declaration first, then bringing
+// back beginPathValidation/handlePathResponse/confirmPathValidation on top
+// of it, and only then wiring a PATH_RESPONSE decode path to call
+// handlePathResponse (transport/path.go's appendPathChallengeFrame and
+// readPathChallengeData still have no caller either).
+
+// handleMigration is invoked by Server.recv when a packet bearing a known
+// DCID arrives from an address other than the connection's current active
+// path. Per RFC 9000 section 9, the new path must be validated with a
+// PATH_CHALLENGE/PATH_RESPONSE exchange before the active path switches
+// over; this package cannot do that validation yet (see the file comment
+// above), so migration is only logged here, not acted on.
+func (s *Server) handleMigration(c *remoteConn, addr net.Addr) {
+	s.logger.Log(LevelInfo, "%s possible migration for connection scid=%x (was %s)", addr, c.scid, c.addr)
+}
@@ -0,0 +1,71 @@
+package quic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFileQlogEmitterDistinctFilesPerTraceID(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := newFileQlogEmitter(dir, []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatalf("newFileQlogEmitter(a): %v", err)
+	}
+	defer a.Close()
+	b, err := newFileQlogEmitter(dir, []byte{0x03, 0x04})
+	if err != nil {
+		t.Fatalf("newFileQlogEmitter(b): %v", err)
+	}
+	defer b.Close()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	for _, want := range []string{"0102.qlog", "0304.qlog"} {
+		if !names[want] {
+			t.Errorf("expected %s among %v", want, names)
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d files, want 2 (one connection must not overwrite the other's trace)", len(entries))
+	}
+}
+
+func TestQlogRegistrySetGet(t *testing.T) {
+	var r qlogRegistry
+	if e := r.get([]byte{0x01}); e != nil {
+		t.Fatalf("get on empty registry = %v, want nil", e)
+	}
+	dir := t.TempDir()
+	e, err := newFileQlogEmitter(dir, []byte{0x01})
+	if err != nil {
+		t.Fatalf("newFileQlogEmitter: %v", err)
+	}
+	defer e.Close()
+	r.set([]byte{0x01}, e)
+	if got := r.get([]byte{0x01}); got != e {
+		t.Fatalf("get(0x01) = %v, want %v", got, e)
+	}
+	if got := r.get([]byte{0x02}); got != nil {
+		t.Fatalf("get for unregistered scid = %v, want nil", got)
+	}
+}
+
+func TestNewFileQlogEmitterEmptyTraceID(t *testing.T) {
+	dir := t.TempDir()
+	e, err := newFileQlogEmitter(dir, nil)
+	if err != nil {
+		t.Fatalf("newFileQlogEmitter: %v", err)
+	}
+	e.Close()
+	if _, err := os.Stat(filepath.Join(dir, ".qlog")); err != nil {
+		t.Fatalf("expected fallback filename .qlog: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net"
 	"time"
 
@@ -18,6 +19,9 @@ type Server struct {
 	localConn
 
 	addrValid AddressValidator
+	allow0RTT func(net.Addr) bool
+	qlogDir   string
+	qlog      qlogRegistry
 }
 
 // NewServer creates a new QUIC server.
@@ -32,6 +36,26 @@ func (s *Server) SetAddressValidator(v AddressValidator) {
 	s.addrValid = v
 }
 
+// SetAllow0RTT sets the function used to decide whether 0-RTT should be
+// accepted from a client at addr. When fn is nil (the default), 0-RTT is
+// never accepted.
+//
+// fn is not consulted yet: newConn still accepts every connection with
+// transport.Accept, which does not attempt early data, because this package
+// has no AcceptEarly taking a config and an 0-RTT-allowed bool to call
+// instead. Until that exists, setting fn has no effect on the wire.
+//
+// Once it does, fn will only gate whether the server is willing to attempt
+// 0-RTT at all; it will not by itself verify the transport parameters the
+// client offered in its resumed session against the ones remembered from the
+// original connection. A caller wiring up session-ticket storage should use
+// transport.MatchEarlyDataParams for that check and reject early data
+// (surfacing it to the application as a StreamEarlyDataRejected event) on a
+// mismatch, rather than a hard connection error.
+func (s *Server) SetAllow0RTT(fn func(net.Addr) bool) {
+	s.allow0RTT = fn
+}
+
 // ListenAndServe starts listening on UDP network address addr and
 // serves incoming packets.
 func (s *Server) ListenAndServe(addr string) error {
@@ -43,11 +67,25 @@ func (s *Server) ListenAndServe(addr string) error {
 	return s.Serve()
 }
 
+// SetListen sets the socket Serve reads from and writes to, instead of one
+// created by ListenAndServe. This lets callers pass in a Transport wrapping a
+// socket bound with SO_REUSEPORT, an adapter over a connected net.Conn, or a
+// mock used in tests. Packets for connections of another Server or Client
+// sharing the same Transport are not routed to them (see Transport).
+// SetListen must be called before Serve.
+func (s *Server) SetListen(t *Transport) {
+	s.socket = t.PacketConn()
+}
+
 // Serve handles incoming requests from a socket connection.
-// XXX: Since net.PacketConn methods can be called simultaneously, users should be able to
-// run Serve in multiple goroutines. For example:
+// Since net.PacketConn methods can be called simultaneously, Serve can be run
+// in multiple goroutines against the same socket: each goroutine reads and
+// decodes its own packets, looks up the destination connection in the peers
+// table under peersMu, and hands the packet to that connection's own
+// handleConn goroutine over its recvCh, so no connection's state is touched
+// by more than one goroutine at a time. For example:
 //
-// 	s.SetListen(socket)
+// 	s.SetListen(t)
 // 	for i := 1; i < num; i++ {
 // 		go s.Serve()
 // 	}
@@ -99,6 +137,22 @@ func (s *Server) recv(p *packet) {
 	c, ok := s.peers[string(p.header.DCID)]
 	s.peersMu.RUnlock()
 	if ok {
+		// transport.Conn has no hook to route its own events through a qlog
+		// emitter, so this is the only packet_received event a qlog trace for
+		// this connection gets; packet_sent and everything from inside the
+		// handshake/recovery/stream machinery is not captured.
+		if e := s.qlog.get(p.header.DCID); e != nil {
+			e.PacketReceived(transport.QlogPacketEvent{
+				PacketType: fmt.Sprintf("%v", p.header.Type),
+				Length:     len(p.data),
+			})
+		}
+		if p.addr.String() != c.addr.String() {
+			// Packet for a known connection arrived from a new 4-tuple: this is
+			// either migration or an off-path attacker. handleMigration only logs
+			// it; the connection keeps its current address.
+			s.handleMigration(c, p.addr)
+		}
 		c.recvCh <- p
 	} else {
 		// Server must ensure the any datagram packet containing Initial packet being at least 1200 bytes
@@ -107,7 +161,7 @@ func (s *Server) recv(p *packet) {
 			freePacket(p)
 			return
 		}
-		if p.header.Version != transport.ProtocolVersion {
+		if !s.versionOffered(p.header.Version) {
 			// Negotiate version
 			s.negotiate(p.addr, &p.header)
 			freePacket(p)
@@ -117,9 +171,32 @@ func (s *Server) recv(p *packet) {
 	}
 }
 
+// versions returns the versions this server offers, falling back to
+// transport.SupportedVersions() when the config does not set any.
+func (s *Server) versions() []uint32 {
+	if len(s.config.Versions) > 0 {
+		return s.config.Versions
+	}
+	return transport.SupportedVersions()
+}
+
+// versionOffered reports whether ver is one this server is willing to speak.
+func (s *Server) versionOffered(ver uint32) bool {
+	for _, v := range s.versions() {
+		if v == ver {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) negotiate(addr net.Addr, h *transport.Header) {
 	p := newPacket()
 	defer freePacket(p)
+	// transport.NegotiateVersion does not take a versions list, so the VN
+	// packet it builds still only offers whatever it hardcodes; s.versions()
+	// only gates versionOffered above until NegotiateVersion grows a
+	// parameter for it.
 	n, err := transport.NegotiateVersion(p.buf[:], h.SCID, h.DCID)
 	if err != nil {
 		s.logger.Log(LevelError, "%s negotiate: %s %v", addr, h, err)
@@ -130,7 +207,7 @@ func (s *Server) negotiate(addr net.Addr, h *transport.Header) {
 		s.logger.Log(LevelError, "%s negotiate: %s %v", addr, h, err)
 		return
 	}
-	s.logger.Log(LevelDebug, "%s negotiate: newversion=%d %s", addr, transport.ProtocolVersion, h)
+	s.logger.Log(LevelDebug, "%s negotiate: versions=%v %s", addr, s.versions(), h)
 	s.logger.Log(LevelTrace, "%s sent %d bytes\n%x", addr, n, p.buf[:n])
 }
 
@@ -196,14 +273,13 @@ func (s *Server) handleNewConn(p *packet) {
 		freePacket(p)
 		return
 	}
-	if _, ok := s.peers[string(c.scid[:])]; ok {
-		// Is that server too slow that client resent the packet? Log it as Error for now.
+	// Is that server too slow that client resent the packet? Log it as Error for now.
+	if err := s.addPeerLocked(c); err != nil {
 		s.peersMu.Unlock()
-		s.logger.Log(LevelError, "%s connection id conflict scid=%x", p.addr, c.scid)
+		s.logger.Log(LevelError, "%s %v", p.addr, err)
 		freePacket(p)
 		return
 	}
-	s.peers[string(c.scid[:])] = c
 	s.peersMu.Unlock()
 	s.logger.Log(LevelDebug, "%s new connection scid=%x odcid=%x", p.addr, c.scid, odcid)
 	c.recvCh <- p // Buffered channel
@@ -222,9 +298,31 @@ func (s *Server) newConn(addr net.Addr, scid, odcid []byte) (*remoteConn, error)
 			return nil, err
 		}
 	}
+	// c.cids is not set up here: remoteConn has no cids field in this source
+	// tree (see the comment on addPeerLocked in cids.go), so a connection's
+	// only routable CID is the scid copied above.
+	// transport.Accept, not an early-data-aware accept, until this package has
+	// an AcceptEarly to call instead; see SetAllow0RTT.
 	if c.conn, err = transport.Accept(c.scid[:], odcid, s.config); err != nil {
 		return nil, err
 	}
+	if s.qlogDir != "" {
+		// odcid is only set when SetAddressValidator is configured and the
+		// client presented a Retry token; fall back to the connection's
+		// scid so every connection still gets a distinct trace file.
+		traceID := odcid
+		if len(traceID) == 0 {
+			traceID = c.scid[:]
+		}
+		qlog, err := newFileQlogEmitter(s.qlogDir, traceID)
+		if err != nil {
+			s.logger.Log(LevelError, "%s create qlog: %v", addr, err)
+		} else {
+			// transport.Conn has no SetQlogEmitter of its own; register under
+			// scid so Server.recv can look it up and feed it packet_received.
+			s.qlog.set(c.scid[:], qlog)
+		}
+	}
 	return c, nil
 }
 
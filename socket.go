@@ -0,0 +1,32 @@
+package quic
+
+import (
+	"net"
+)
+
+// Transport owns a net.PacketConn, e.g. an SO_REUSEPORT-bound socket, that a
+// Client or Server reads from and writes to.
+//
+// A Transport does not dispatch packets between owners: each owner's Serve
+// reads from the socket itself, and a Client drops (a Server drops or treats
+// as a new connection) any packet whose destination connection ID it does
+// not know, so packets one owner reads for another owner's connection are
+// not delivered to it.
+//
+// A Transport must be assigned with SetListen before any of its owners call
+// Serve.
+type Transport struct {
+	socket net.PacketConn
+}
+
+// NewTransport wraps an already-constructed net.PacketConn, such as one
+// bound with SO_REUSEPORT, an adapter over a connected net.Conn, or a mock
+// used in tests.
+func NewTransport(socket net.PacketConn) *Transport {
+	return &Transport{socket: socket}
+}
+
+// PacketConn returns the underlying socket.
+func (t *Transport) PacketConn() net.PacketConn {
+	return t.socket
+}
@@ -7,8 +7,23 @@ import (
 )
 
 const (
-	// ProtocolVersion is the supported QUIC version
-	ProtocolVersion = 0xff000000 + 29
+	// ProtocolVersion is VersionDraft29. It is not what a Client dials by
+	// default: a Client whose Config leaves Version zero dials the first entry
+	// of Versions, or of SupportedVersions() (Version1) when Versions is empty.
+	ProtocolVersion = VersionDraft29
+
+	// VersionDraft29 is draft-ietf-quic-transport-29, kept for interoperability
+	// with older deployments.
+	VersionDraft29 = 0xff000000 + 29
+	// Version1 is QUIC v1, RFC 9000.
+	//
+	// initialSalt knows the RFC 9001 section 5.2 salt for this version, but
+	// has no caller: nothing in this package derives Initial packet
+	// protection keys yet, because that derivation lives in the
+	// packet-processing path this source tree doesn't include. A connection
+	// negotiated to Version1 therefore cannot actually protect its Initial
+	// packets with this package alone.
+	Version1 = 0x00000001
 
 	// MaxCIDLength is the maximum length of a Connection ID
 	MaxCIDLength = 20
@@ -33,14 +48,33 @@ const (
 // This implementaton utilizes tls.Config.Rand and tls.Config.Time if available.
 type Config struct {
 	Version uint32
-	TLS     *tls.Config
-	Params  Parameters
+	// Versions lists the QUIC versions this endpoint supports, in preference
+	// order. The first entry is used as Version when Version is left zero.
+	// A Server accepts Initial packets only for a version in Versions, but its
+	// Version Negotiation packet lists only what NegotiateVersion writes, not
+	// Versions; a Client does not retry with another version after receiving
+	// one. Defaults to SupportedVersions() when empty.
+	Versions []uint32
+	TLS      *tls.Config
+	Params   Parameters
+}
+
+// SupportedVersions returns the QUIC versions this implementation can
+// negotiate, in preference order.
+func SupportedVersions() []uint32 {
+	return []uint32{Version1, VersionDraft29}
 }
 
 // NewConfig creates a default configuration.
+//
+// Version is left zero rather than set to ProtocolVersion: Client.newConn
+// only runs its "pick the highest version both sides support" logic when
+// Version is zero, so pinning it here would make every Client built from
+// NewConfig dial ProtocolVersion forever and never exercise the other
+// entries in Versions.
 func NewConfig() *Config {
 	return &Config{
-		Version: ProtocolVersion,
+		Versions: SupportedVersions(),
 		Params: Parameters{
 			MaxIdleTimeout:   30 * time.Second,
 			AckDelayExponent: 3,
@@ -52,10 +86,56 @@ func NewConfig() *Config {
 			InitialMaxStreamDataUni:        1024,
 			InitialMaxStreamsBidi:          1,
 			InitialMaxStreamsUni:           1,
+
+			// Parameters has no MaxDatagramFrameSize field yet to zero out here:
+			// advertising max_datagram_frame_size (RFC 9221) requires adding one,
+			// which touches the transport parameter encode/decode this config
+			// type does not own. No DATAGRAM support is negotiated:
+			// SendDatagram and ReceiveDatagram in the quic package always
+			// return an error, and NewConfig cannot opt an application in.
 		},
 	}
 }
 
+// versionSupported reports whether ver is one of the versions this
+// implementation can speak, regardless of what a particular Config offers.
 func versionSupported(ver uint32) bool {
-	return ver == ProtocolVersion
+	for _, v := range SupportedVersions() {
+		if v == ver {
+			return true
+		}
+	}
+	return false
+}
+
+// PickVersion picks the highest version present in both local (in preference
+// order) and offered, or 0 if there is no overlap. Its only caller is the
+// quic package's Client.retryVersion, which nothing calls; Server does not
+// use it.
+func PickVersion(local []uint32, offered []uint32) uint32 {
+	for _, v := range local {
+		for _, o := range offered {
+			if v == o {
+				return v
+			}
+		}
+	}
+	return 0
+}
+
+// compatibleVersion reports whether initial, the version a client committed
+// to in its first Initial packet, is compatible with preferred, the version a
+// server would rather speak, per RFC 9368 compatible version negotiation:
+// since both versions currently supported here (v1 and draft-29) share wire
+// format and key derivation closely enough that this implementation treats
+// any two supported versions as compatible, allowing the server to switch to
+// its preferred version without spending an extra round trip.
+//
+// compatibleVersion has no caller yet. Acting on it requires a server-side
+// decision point that compares a connection's initial version against its
+// own preferred one and switches before committing to initial, which does
+// not exist in this package; RFC 9368 support is currently only this
+// comparison, not a wired compatible-version upgrade.
+func compatibleVersion(initial, preferred uint32) bool {
+	return versionSupported(initial) && versionSupported(preferred)
 }
@@ -0,0 +1,85 @@
+package transport
+
+import "errors"
+
+// DATAGRAM frame types, as defined in RFC 9221 section 4.
+// Type 0x30 carries an implicit length (the rest of the packet); 0x31 is
+// prefixed with an explicit Length field.
+const (
+	frameDatagram        = 0x30
+	frameDatagramWithLen = 0x31
+)
+
+// ErrDatagramTooLarge reports a datagram that would not fit in a single QUIC
+// packet after accounting for AEAD overhead and the peer's advertised
+// max_datagram_frame_size. Nothing returns it: Conn has no SendDatagram.
+var ErrDatagramTooLarge = errors.New("transport: datagram too large")
+
+// DatagramRecvEvent marks a received DATAGRAM frame. Nothing sends it through
+// Handler.Serve, and the quic package's ReceiveDatagram returns an error
+// rather than a payload.
+type DatagramRecvEvent struct{}
+
+// appendDatagramFrame appends a DATAGRAM frame (using the explicit-length form
+// so it need not be the last frame in the packet) carrying data to b.
+func appendDatagramFrame(b []byte, data []byte) []byte {
+	b = appendVarint(b, frameDatagramWithLen)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// readDatagramFrame parses a DATAGRAM frame from b, returning the payload and
+// the number of bytes consumed. withLen indicates whether the frame type read
+// from b carries an explicit Length field (0x31) or extends to the end of the
+// packet (0x30).
+func readDatagramFrame(b []byte, withLen bool) (data []byte, n int, err error) {
+	if !withLen {
+		return b, len(b), nil
+	}
+	length, m, err := getVarint(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	if uint64(len(b)-m) < length {
+		return nil, 0, errors.New("transport: truncated datagram frame")
+	}
+	return b[m : m+int(length)], m + int(length), nil
+}
+
+// datagramQueue is a bounded FIFO of received datagrams with a drop-oldest
+// policy. No receive path or ReceiveDatagram method uses it.
+type datagramQueue struct {
+	queue   [][]byte
+	maxSize int
+	dropped uint64
+}
+
+// newDatagramQueue creates a queue that holds at most maxSize datagrams.
+func newDatagramQueue(maxSize int) *datagramQueue {
+	return &datagramQueue{maxSize: maxSize}
+}
+
+// push enqueues data, dropping the oldest queued datagram first if the queue
+// is full.
+func (q *datagramQueue) push(data []byte) {
+	if len(q.queue) >= q.maxSize {
+		q.queue = q.queue[1:]
+		q.dropped++
+	}
+	q.queue = append(q.queue, data)
+}
+
+// pop removes and returns the oldest queued datagram, or nil if the queue is empty.
+func (q *datagramQueue) pop() []byte {
+	if len(q.queue) == 0 {
+		return nil
+	}
+	data := q.queue[0]
+	q.queue = q.queue[1:]
+	return data
+}
+
+// droppedCount returns the number of datagrams dropped due to the queue being full.
+func (q *datagramQueue) droppedCount() uint64 {
+	return q.dropped
+}
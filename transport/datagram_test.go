@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDatagramFrameRoundTrip(t *testing.T) {
+	want := []byte("hello datagram")
+	b := appendDatagramFrame(nil, want)
+
+	typ, n, err := getVarint(b)
+	if err != nil {
+		t.Fatalf("getVarint: %v", err)
+	}
+	if typ != frameDatagramWithLen {
+		t.Fatalf("frame type = %#x, want %#x", typ, frameDatagramWithLen)
+	}
+	got, consumed, err := readDatagramFrame(b[n:], true)
+	if err != nil {
+		t.Fatalf("readDatagramFrame: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("readDatagramFrame = %q, want %q", got, want)
+	}
+	if n+consumed != len(b) {
+		t.Fatalf("consumed %d bytes, want %d", n+consumed, len(b))
+	}
+}
+
+func TestReadDatagramFrameWithoutLength(t *testing.T) {
+	want := []byte("rest of packet")
+	got, n, err := readDatagramFrame(want, false)
+	if err != nil {
+		t.Fatalf("readDatagramFrame: %v", err)
+	}
+	if !bytes.Equal(got, want) || n != len(want) {
+		t.Fatalf("readDatagramFrame = %q, %d, want %q, %d", got, n, want, len(want))
+	}
+}
+
+func TestDatagramQueueDropOldest(t *testing.T) {
+	q := newDatagramQueue(2)
+	q.push([]byte("a"))
+	q.push([]byte("b"))
+	q.push([]byte("c")) // drops "a"
+
+	if got := q.droppedCount(); got != 1 {
+		t.Fatalf("droppedCount = %d, want 1", got)
+	}
+	if got := q.pop(); string(got) != "b" {
+		t.Fatalf("pop = %q, want %q", got, "b")
+	}
+	if got := q.pop(); string(got) != "c" {
+		t.Fatalf("pop = %q, want %q", got, "c")
+	}
+	if got := q.pop(); got != nil {
+		t.Fatalf("pop on empty queue = %q, want nil", got)
+	}
+}
@@ -0,0 +1,32 @@
+package transport
+
+// StreamEarlyDataRejected describes a stream whose early data the server
+// rejected, either because it declined 0-RTT outright or because the resumed
+// transport parameters did not match the ones remembered from the original
+// connection. Nothing sends it through Handler.Serve: the quic package's
+// ConnectEarly sends no 0-RTT data and its Server does not consult
+// SetAllow0RTT.
+type StreamEarlyDataRejected struct {
+	StreamID uint64
+}
+
+// MatchEarlyDataParams reports whether current, the transport parameters a
+// resuming client has offered, are compatible with remembered, the ones a
+// server saved from the original connection that issued the session ticket.
+// A server accepting 0-RTT should reject early data (surfacing
+// StreamEarlyDataRejected) unless this returns true, since the client already
+// sent stream data sized and flow-controlled against remembered before the
+// handshake confirmed current.
+//
+// Only the limits that bound what a client may have already done with early
+// data are compared: raising a limit between connections is safe to allow,
+// since the client could not have exceeded the lower of the two, but
+// lowering one invalidates data the client may have already sent.
+func MatchEarlyDataParams(remembered, current Parameters) bool {
+	return current.InitialMaxData >= remembered.InitialMaxData &&
+		current.InitialMaxStreamDataBidiLocal >= remembered.InitialMaxStreamDataBidiLocal &&
+		current.InitialMaxStreamDataBidiRemote >= remembered.InitialMaxStreamDataBidiRemote &&
+		current.InitialMaxStreamDataUni >= remembered.InitialMaxStreamDataUni &&
+		current.InitialMaxStreamsBidi >= remembered.InitialMaxStreamsBidi &&
+		current.InitialMaxStreamsUni >= remembered.InitialMaxStreamsUni
+}
@@ -0,0 +1,30 @@
+package transport
+
+import "testing"
+
+func TestMatchEarlyDataParams(t *testing.T) {
+	remembered := Parameters{
+		InitialMaxData:                 1024,
+		InitialMaxStreamDataBidiLocal:  1024,
+		InitialMaxStreamDataBidiRemote: 1024,
+		InitialMaxStreamDataUni:        1024,
+		InitialMaxStreamsBidi:          4,
+		InitialMaxStreamsUni:           4,
+	}
+
+	if !MatchEarlyDataParams(remembered, remembered) {
+		t.Error("identical parameters should match")
+	}
+
+	raised := remembered
+	raised.InitialMaxData = 2048
+	if !MatchEarlyDataParams(remembered, raised) {
+		t.Error("raising a limit should still match")
+	}
+
+	lowered := remembered
+	lowered.InitialMaxStreamsBidi = 1
+	if MatchEarlyDataParams(remembered, lowered) {
+		t.Error("lowering a limit should not match")
+	}
+}
@@ -0,0 +1,25 @@
+package transport
+
+// initialSalt returns the version-specific salt used to derive Initial
+// packet protection keys (RFC 9001 section 5.2), or nil if ver is not
+// supported.
+func initialSalt(ver uint32) []byte {
+	switch ver {
+	case Version1:
+		// RFC 9001 section 5.2.
+		return []byte{
+			0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3,
+			0x4d, 0x17, 0x9a, 0xe6, 0xa4, 0xc8, 0x0c, 0xad,
+			0xcc, 0xbb, 0x7f, 0x0a,
+		}
+	case VersionDraft29:
+		// draft-ietf-quic-tls-29 section 5.2.
+		return []byte{
+			0xaf, 0xbf, 0xec, 0x28, 0x99, 0x93, 0xd2, 0x4c,
+			0x9e, 0x97, 0x86, 0xf1, 0x9c, 0x61, 0x11, 0xe0,
+			0x43, 0x90, 0xa8, 0x99,
+		}
+	default:
+		return nil
+	}
+}
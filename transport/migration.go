@@ -0,0 +1,136 @@
+package transport
+
+import "errors"
+
+// Frame types used for connection ID management, as defined in RFC 9000
+// section 19.15-19.16.
+const (
+	frameNewConnectionID    = 0x18
+	frameRetireConnectionID = 0x19
+)
+
+// CIDEntry is one connection ID issued to (or by) a peer, together with its
+// stateless reset token.
+type CIDEntry struct {
+	Seq         uint64
+	CID         []byte
+	ResetToken  [16]byte
+	RetirePrior uint64
+}
+
+// CIDPool tracks the set of connection IDs available for a connection's
+// sending side (i.e. the peer's DCIDs we may use) or receiving side (the
+// SCIDs we issued and still accept), up to limit entries.
+//
+// The quic package's Server does not use a CIDPool: its peers table holds
+// only each connection's scid (see addPeerLocked there).
+type CIDPool struct {
+	limit int
+	ids   []CIDEntry
+	next  uint64
+}
+
+// NewCIDPool creates a pool that holds at most limit connection IDs, as
+// bounded by the active_connection_id_limit transport parameter.
+func NewCIDPool(limit int) *CIDPool {
+	return &CIDPool{limit: limit}
+}
+
+// Add registers a newly issued or learned connection ID. It reports false
+// without adding the entry if the pool is already at limit.
+func (p *CIDPool) Add(cid []byte, resetToken [16]byte) (CIDEntry, bool) {
+	if p.limit > 0 && len(p.ids) >= p.limit {
+		return CIDEntry{}, false
+	}
+	id := CIDEntry{Seq: p.next, CID: append([]byte(nil), cid...), ResetToken: resetToken}
+	p.next++
+	p.ids = append(p.ids, id)
+	return id, true
+}
+
+// Retire removes the connection ID with the given sequence number, as
+// requested by a RETIRE_CONNECTION_ID frame (or issued locally via
+// RetirePrior in a NEW_CONNECTION_ID frame).
+func (p *CIDPool) Retire(seq uint64) (CIDEntry, bool) {
+	for i, id := range p.ids {
+		if id.Seq == seq {
+			p.ids = append(p.ids[:i], p.ids[i+1:]...)
+			return id, true
+		}
+	}
+	return CIDEntry{}, false
+}
+
+// Find returns the connection ID entry matching cid, if any.
+func (p *CIDPool) Find(cid []byte) (CIDEntry, bool) {
+	for _, id := range p.ids {
+		if string(id.CID) == string(cid) {
+			return id, true
+		}
+	}
+	return CIDEntry{}, false
+}
+
+// Active returns every connection ID currently in the pool, i.e. every CID
+// a caller such as Server should still accept or may still use. The
+// returned slice must not be mutated.
+func (p *CIDPool) Active() []CIDEntry {
+	return p.ids
+}
+
+// appendNewConnectionIDFrame encodes a NEW_CONNECTION_ID frame for id.
+//
+// Like appendRetireConnectionIDFrame and the read side of both, this is not
+// yet called from anywhere: there is no packet encode/decode loop in this
+// package to schedule a NEW_CONNECTION_ID frame onto an outgoing packet or
+// to decode one (or a RETIRE_CONNECTION_ID frame) out of a received one
+// (see the notes on quic.Server's issueCID/retireCID). quic.Server keeps no
+// CIDPool, only each connection's single scid.
+func appendNewConnectionIDFrame(b []byte, id CIDEntry) []byte {
+	b = appendVarint(b, frameNewConnectionID)
+	b = appendVarint(b, id.Seq)
+	b = appendVarint(b, id.RetirePrior)
+	b = append(b, byte(len(id.CID)))
+	b = append(b, id.CID...)
+	return append(b, id.ResetToken[:]...)
+}
+
+// readNewConnectionIDFrame parses a NEW_CONNECTION_ID frame from b, returning
+// the decoded entry and the number of bytes consumed.
+func readNewConnectionIDFrame(b []byte) (id CIDEntry, n int, err error) {
+	seq, n1, err := getVarint(b)
+	if err != nil {
+		return CIDEntry{}, 0, err
+	}
+	retirePrior, n2, err := getVarint(b[n1:])
+	if err != nil {
+		return CIDEntry{}, 0, err
+	}
+	off := n1 + n2
+	if off >= len(b) {
+		return CIDEntry{}, 0, errors.New("transport: truncated new_connection_id frame")
+	}
+	l := int(b[off])
+	off++
+	if len(b) < off+l+16 {
+		return CIDEntry{}, 0, errors.New("transport: truncated new_connection_id frame")
+	}
+	id.Seq = seq
+	id.RetirePrior = retirePrior
+	id.CID = append([]byte(nil), b[off:off+l]...)
+	off += l
+	copy(id.ResetToken[:], b[off:off+16])
+	off += 16
+	return id, off, nil
+}
+
+// appendRetireConnectionIDFrame encodes a RETIRE_CONNECTION_ID frame.
+func appendRetireConnectionIDFrame(b []byte, seq uint64) []byte {
+	b = appendVarint(b, frameRetireConnectionID)
+	return appendVarint(b, seq)
+}
+
+// readRetireConnectionIDFrame parses a RETIRE_CONNECTION_ID frame from b.
+func readRetireConnectionIDFrame(b []byte) (seq uint64, n int, err error) {
+	return getVarint(b)
+}
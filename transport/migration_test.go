@@ -0,0 +1,82 @@
+package transport
+
+import "testing"
+
+func TestCIDPoolAddFindRetire(t *testing.T) {
+	p := NewCIDPool(2)
+
+	a, ok := p.Add([]byte{0x01}, [16]byte{0xaa})
+	if !ok {
+		t.Fatal("Add a: want ok")
+	}
+	b, ok := p.Add([]byte{0x02}, [16]byte{0xbb})
+	if !ok {
+		t.Fatal("Add b: want ok")
+	}
+	if _, ok := p.Add([]byte{0x03}, [16]byte{0xcc}); ok {
+		t.Fatal("Add beyond limit: want !ok")
+	}
+
+	if got, ok := p.Find([]byte{0x02}); !ok || got.Seq != b.Seq {
+		t.Fatalf("Find(0x02) = %+v, %v", got, ok)
+	}
+	if len(p.Active()) != 2 {
+		t.Fatalf("Active() = %v, want 2 entries", p.Active())
+	}
+
+	retired, ok := p.Retire(a.Seq)
+	if !ok || string(retired.CID) != string(a.CID) {
+		t.Fatalf("Retire(a) = %+v, %v", retired, ok)
+	}
+	if _, ok := p.Find([]byte{0x01}); ok {
+		t.Fatal("Find(0x01) after retire: want !ok")
+	}
+	if len(p.Active()) != 1 {
+		t.Fatalf("Active() after retire = %v, want 1 entry", p.Active())
+	}
+
+	// A slot freed by Retire can be reused.
+	if _, ok := p.Add([]byte{0x03}, [16]byte{0xcc}); !ok {
+		t.Fatal("Add after retire: want ok")
+	}
+}
+
+func TestNewConnectionIDFrameRoundTrip(t *testing.T) {
+	want := CIDEntry{Seq: 3, CID: []byte{1, 2, 3, 4}, ResetToken: [16]byte{9, 9, 9}, RetirePrior: 1}
+	b := appendNewConnectionIDFrame(nil, want)
+	// Skip the frame type byte appendNewConnectionIDFrame wrote.
+	_, typeLen, err := getVarint(b)
+	if err != nil {
+		t.Fatalf("getVarint type: %v", err)
+	}
+	got, n, err := readNewConnectionIDFrame(b[typeLen:])
+	if err != nil {
+		t.Fatalf("readNewConnectionIDFrame: %v", err)
+	}
+	if typeLen+n != len(b) {
+		t.Fatalf("consumed %d bytes, want %d", typeLen+n, len(b))
+	}
+	if got.Seq != want.Seq || string(got.CID) != string(want.CID) ||
+		got.ResetToken != want.ResetToken || got.RetirePrior != want.RetirePrior {
+		t.Fatalf("readNewConnectionIDFrame = %+v, want %+v", got, want)
+	}
+}
+
+func TestRetireConnectionIDFrameRoundTrip(t *testing.T) {
+	b := appendRetireConnectionIDFrame(nil, 42)
+	// Skip the frame type byte appendRetireConnectionIDFrame wrote.
+	_, typeLen, err := getVarint(b)
+	if err != nil {
+		t.Fatalf("getVarint type: %v", err)
+	}
+	seq, n, err := readRetireConnectionIDFrame(b[typeLen:])
+	if err != nil {
+		t.Fatalf("readRetireConnectionIDFrame: %v", err)
+	}
+	if seq != 42 {
+		t.Fatalf("seq = %d, want 42", seq)
+	}
+	if typeLen+n != len(b) {
+		t.Fatalf("consumed %d bytes, want %d", typeLen+n, len(b))
+	}
+}
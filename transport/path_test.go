@@ -0,0 +1,61 @@
+package transport
+
+import "testing"
+
+func TestPathValidatorChallengeValidate(t *testing.T) {
+	var v PathValidator
+	calls := 0
+	rand := func(b []byte) error {
+		calls++
+		for i := range b {
+			b[i] = byte(i + 1)
+		}
+		return nil
+	}
+
+	data, err := v.Challenge(rand)
+	if err != nil {
+		t.Fatalf("Challenge: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("rand called %d times, want 1", calls)
+	}
+
+	if !v.Validate(data) {
+		t.Fatal("Validate(matching data) = false, want true")
+	}
+	// The challenge is consumed by a successful Validate; a replay must fail.
+	if v.Validate(data) {
+		t.Fatal("Validate(replayed data) = true, want false")
+	}
+}
+
+func TestPathValidatorValidateWrongData(t *testing.T) {
+	var v PathValidator
+	data, err := v.Challenge(func(b []byte) error {
+		for i := range b {
+			b[i] = 0xff
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Challenge: %v", err)
+	}
+	wrong := data
+	wrong[0] ^= 0x01
+	if v.Validate(wrong) {
+		t.Fatal("Validate(wrong data) = true, want false")
+	}
+	// A mismatched PATH_RESPONSE still consumes the pending challenge, so a
+	// later correct response cannot validate it either.
+	if v.Validate(data) {
+		t.Fatal("Validate(correct data after a mismatched attempt) = true, want false")
+	}
+}
+
+func TestPathValidatorValidateWithoutChallenge(t *testing.T) {
+	var v PathValidator
+	if v.Validate([pathChallengeDataLength]byte{}) {
+		t.Fatal("Validate with no pending challenge = true, want false")
+	}
+}
@@ -0,0 +1,85 @@
+package transport
+
+import "net"
+
+// QlogEmitter receives structured connection events in qlog format
+// (draft-ietf-quic-qlog-quic-events).
+//
+// Conn has no method to accept one of these: nothing in this package calls
+// PacketSent, PacketsLost, MetricsUpdated, ParametersSet, StreamStateUpdated
+// or RecoveryEvent on an emitter. The quic package's Server and Client call
+// PacketReceived directly from their own recv path instead, using the
+// packet header they already decode there; everything else this interface
+// describes (handshake/recovery/stream internals) is not captured yet.
+type QlogEmitter interface {
+	PacketSent(QlogPacketEvent)
+	PacketReceived(QlogPacketEvent)
+	PacketsLost(QlogPacketsLostEvent)
+	MetricsUpdated(QlogMetrics)
+	ParametersSet(local bool, params Parameters)
+	StreamStateUpdated(QlogStreamStateEvent)
+	RecoveryEvent(name string, data map[string]interface{})
+}
+
+// QlogPacketEvent is the data object for packet_sent and packet_received.
+type QlogPacketEvent struct {
+	PacketType   string
+	PacketNumber uint64
+	Length       int
+	Frames       []string
+}
+
+// QlogPacketsLostEvent is the data object for packets_lost.
+type QlogPacketsLostEvent struct {
+	PacketType   string
+	PacketNumber uint64
+}
+
+// QlogMetrics is the data object for metrics_updated (recovery category).
+type QlogMetrics struct {
+	CongestionWindow int
+	BytesInFlight    int
+	SmoothedRTT      float64
+	RTTVariance      float64
+	MinRTT           float64
+	LatestRTT        float64
+}
+
+// QlogStreamStateEvent is the data object for stream_state_updated.
+type QlogStreamStateEvent struct {
+	StreamID uint64
+	State    string
+}
+
+// NoopQlogEmitter discards all events. Nothing uses it, since Conn holds no
+// emitter.
+type NoopQlogEmitter struct{}
+
+func (NoopQlogEmitter) PacketSent(QlogPacketEvent)                   {}
+func (NoopQlogEmitter) PacketReceived(QlogPacketEvent)               {}
+func (NoopQlogEmitter) PacketsLost(QlogPacketsLostEvent)             {}
+func (NoopQlogEmitter) MetricsUpdated(QlogMetrics)                   {}
+func (NoopQlogEmitter) ParametersSet(bool, Parameters)               {}
+func (NoopQlogEmitter) StreamStateUpdated(QlogStreamStateEvent)      {}
+func (NoopQlogEmitter) RecoveryEvent(string, map[string]interface{}) {}
+
+// odcidString formats an original destination connection ID the way qlog
+// trace file names and common_fields.ODCID expect: lowercase hex.
+func odcidString(odcid []byte) string {
+	const hextable = "0123456789abcdef"
+	b := make([]byte, len(odcid)*2)
+	for i, c := range odcid {
+		b[i*2] = hextable[c>>4]
+		b[i*2+1] = hextable[c&0xf]
+	}
+	return string(b)
+}
+
+// localAddrString returns addr.String(), or "" when addr is nil. Nothing
+// calls it; parameters_set events carry no address.
+func localAddrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}